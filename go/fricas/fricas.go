@@ -2,12 +2,18 @@ package fricas
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 )
 
 const (
@@ -15,103 +21,538 @@ const (
 	// FriCAS package(s).
 	FricasLibDir = "/home/nsajko/src/github.com/nsajko/numericcompfricas/fricas"
 
-	// How many lines of Fricas's output to discard when it is
-	// started. (Start-up messages, etc.)
-	FloatFricasLinesToSkipAtStartup = 17
+	// DefaultFloatFricasBits is the precision, in bits, used by
+	// FloatFricas values that don't set FloatFricasBits themselves.
+	DefaultFloatFricasBits = "32768"
 
-	// Bits of precision for floating point representation.
-	FloatFricasBits = "32768"
+	// promptSentinel is configured as Fricas's prompt text (via )set
+	// messages prompt string) so a session's output can be split into
+	// exactly the text produced between one prompt and the next,
+	// regardless of banner length or how many lines a result spans.
+	promptSentinel = "__GOFRICAS>__"
 )
 
-type FloatFricas struct {
-	in io.WriteCloser
-	out io.ReadCloser
+// session holds the subprocess plumbing shared by every Fricas
+// wrapper type in this package: a single long-lived "fricas -nosman"
+// child process, talked to over its stdin/stdout pipes, plus the
+// dispatcher goroutine that serializes access to it.
+type session struct {
+	in    io.WriteCloser
+	out   io.ReadCloser
 	inBuf *bufio.Writer
-	outBuf *bufio.Reader
+	scan  *bufio.Scanner
+	proc  *os.Process
+
+	dispatchOnce sync.Once
+	requests     chan sessionRequest
 }
 
-// TODO: provide access to Fricas's interval computation facilities
-// (package Interval)?
+// sessionRequest is one pending evaluation: write input, then read
+// back one parsed output block.
+type sessionRequest struct {
+	ctx    context.Context
+	input  string
+	respCh chan sessionResponse
+}
 
-func FloatEval(f *FloatFricas, fricasCmd string, x float64) float64 {
-	// Lexes the floating point value from lines like these that
-	// Fricas outputs (note the space after the minus sign):
-	//
-	//    (13)  0.3300000000000000000000000E1
-	//    (1)  - 0.3300000000000000000000000E1
-
-	go func() {
-		if _, err := fmt.Fprintf(f.inBuf, fricasCmd, x); err != nil {
-			fmt.Fprintf(os.Stderr, "goFricas: fmt.Fprintf(f.inBuf, fricasCmd, x): %v\n", err)
+type sessionResponse struct {
+	raw string
+	err error
+}
+
+// ensureDispatcher starts the goroutine that serializes access to
+// s.inBuf/s.scan, the first time it's needed. Every evaluation, from
+// any goroutine and for any wrapper type sharing this session, goes
+// through s.requests so concurrent callers never interleave writes or
+// race over who reads which reply.
+func (s *session) ensureDispatcher() {
+	s.dispatchOnce.Do(func() {
+		s.requests = make(chan sessionRequest)
+		go s.dispatch()
+	})
+}
+
+func (s *session) dispatch() {
+	for req := range s.requests {
+		if _, err := s.inBuf.WriteString(req.input); err != nil {
+			req.respCh <- sessionResponse{err: fmt.Errorf("goFricas: s.inBuf.WriteString: %w", err)}
+			continue
 		}
-		if err := f.inBuf.Flush(); err != nil {
-			fmt.Fprintf(os.Stderr, "goFricas: f.inBuf.Flush: %v\n", err)
+		if err := s.inBuf.Flush(); err != nil {
+			req.respCh <- sessionResponse{err: fmt.Errorf("goFricas: s.inBuf.Flush: %w", err)}
+			continue
 		}
-	}()
 
-	// TODO: Maybe return a custom NaN for each error?
+		read := make(chan sessionResponse, 1)
+		go func() {
+			raw, err := s.block()
+			read <- sessionResponse{raw: raw, err: err}
+		}()
 
-	// Skip "[^)]*)  ".
-	if _, err := f.outBuf.ReadSlice(')'); err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: f.outBuf.ReadSlice(')'): %v\n", err)
-		return math.NaN()
-	}
-	if _, err := f.outBuf.ReadByte(); err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: f.outBuf.ReadByte 0: %v\n", err)
-		return math.NaN()
+		select {
+		case resp := <-read:
+			req.respCh <- resp
+		case <-req.ctx.Done():
+			s.interrupt()
+			<-read // drain the aborted reply so the next request lines up
+			req.respCh <- sessionResponse{err: req.ctx.Err()}
+		}
 	}
-	if _, err := f.outBuf.ReadByte(); err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: f.outBuf.ReadByte 1: %v\n", err)
-		return math.NaN()
+}
+
+// eval sends input to the dispatcher and waits for its reply,
+// honoring ctx cancellation.
+func (s *session) eval(ctx context.Context, input string) (string, error) {
+	s.ensureDispatcher()
+	respCh := make(chan sessionResponse, 1)
+	s.requests <- sessionRequest{ctx: ctx, input: input, respCh: respCh}
+	resp := <-respCh
+	return resp.raw, resp.err
+}
+
+// startSession launches "fricas -nosman" with the given -eval
+// arguments, wires up buffered stdin and a prompt-delimited stdout
+// scanner, and discards the start-up banner (everything up to the
+// first prompt).
+func startSession(evalArgs []string) (*session, error) {
+	args := make([]string, 0, 2*len(evalArgs)+1)
+	args = append(args, "-nosman")
+	for _, e := range evalArgs {
+		args = append(args, "-eval", e)
 	}
+	cmd := exec.Command("fricas", args...)
 
-	num, err := f.outBuf.ReadSlice('\n')
+	s := new(session)
+	var err error
+	s.in, err = cmd.StdinPipe()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: f.outBuf.ReadSlice('\\n'): %v\n", err)
-		return math.NaN()
+		return nil, fmt.Errorf("goFricas: cmd.StdinPipe: %w", err)
+	}
+	s.out, err = cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("goFricas: cmd.StdoutPipe: %w", err)
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("goFricas: cmd.Start: %w", err)
+	}
+	s.proc = cmd.Process
+	s.inBuf = bufio.NewWriter(s.in)
+	s.scan = bufio.NewScanner(s.out)
+	s.scan.Buffer(make([]byte, 0, 4096), 1<<20)
+	s.scan.Split(splitOnPrompt)
+
+	if !s.scan.Scan() {
+		return nil, fmt.Errorf("goFricas: reading start-up banner: %w", s.scan.Err())
+	}
+	return s, nil
+}
+
+// splitOnPrompt is a bufio.SplitFunc that returns everything preceding
+// the next occurrence of promptSentinel as a token, consuming the
+// sentinel itself.
+func splitOnPrompt(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.Index(data, []byte(promptSentinel)); i >= 0 {
+		return i + len(promptSentinel), data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// interrupt asks the child to abandon whatever it's currently
+// evaluating: SIGINT stops the computation and Fricas's REPL recovers
+// to a fresh prompt on its own. Don't write anything to resync with —
+// under prompt-delimited splitting (unlike the old "(NN)" counter),
+// every accepted input line produces its own prompt cycle, so writing
+// an extra line here would leave a second, never-drained cycle in the
+// stream that the next caller's block() would consume instead of its
+// own result.
+func (s *session) interrupt() {
+	if s.proc != nil {
+		s.proc.Signal(syscall.SIGINT)
+	}
+}
+
+func (s *session) Close() error {
+	if err := s.in.Close(); err != nil {
+		return err
 	}
+	return s.out.Close()
+}
+
+// block reads the next prompt-delimited block of output, strips its
+// result-counter marker and returns the remaining text with whitespace
+// collapsed and a leading "- " joined to "-" (FriCAS writes a space
+// after a minus sign). Non-empty text that does not start with a digit, "-" or
+// "[" is returned as a Fricas error. The text itself is not parsed:
+// whether it is a number, list or interval is left to the caller.
+func (s *session) block() (string, error) {
+	if !s.scan.Scan() {
+		if err := s.scan.Err(); err != nil {
+			return "", fmt.Errorf("s.scan: %w", err)
+		}
+		return "", fmt.Errorf("s.scan: unexpected end of Fricas output")
+	}
+
+	rest, ok := stripResultMarker(s.scan.Text())
+	if !ok {
+		return "", fmt.Errorf("unrecognized Fricas output: %q", s.scan.Text())
+	}
+	rest = strings.Join(strings.Fields(rest), " ")
+	if strings.HasPrefix(rest, "- ") {
+		rest = "-" + rest[len("- "):]
+	}
+	if rest != "" && rest[0] != '-' && rest[0] != '[' && (rest[0] < '0' || rest[0] > '9') {
+		return "", fmt.Errorf("fricas error: %s", rest)
+	}
+	return rest, nil
+}
+
+// stripResultMarker removes everything up to and including the first
+// "(NN)" result-counter marker, and the spaces after it, reporting
+// whether one was found. If the first "(" in block does not open a
+// marker, no later one is tried.
+func stripResultMarker(block string) (string, bool) {
+	i := strings.IndexByte(block, '(')
+	if i < 0 {
+		return block, false
+	}
+	j := strings.IndexByte(block[i:], ')')
+	if j < 0 {
+		return block, false
+	}
+	j += i
+	for _, c := range block[i+1 : j] {
+		if c < '0' || c > '9' {
+			return block, false
+		}
+	}
+	return strings.TrimLeft(block[j+1:], " "), true
+}
+
+type FloatFricas struct {
+	*session
+
+	// FloatFricasBits is the bits of precision for Fricas's Float
+	// domain. Must be set before calling NewFloatFricas; leave it
+	// empty to fall back to DefaultFloatFricasBits.
+	FloatFricasBits string
+}
 
-	if num[0] == '-' {
-		// Remove the extra space character after the -.
-		num = num[1:]
-		num[0] = '-'
+// EvalContext substitutes x into fricasCmd and evaluates it, the
+// context-aware, error-returning counterpart of FloatEval. If ctx is
+// canceled before Fricas replies, EvalContext interrupts the child and
+// returns ctx.Err().
+func (f *FloatFricas) EvalContext(ctx context.Context, fricasCmd string, x float64) (float64, error) {
+	raw, err := f.eval(ctx, fmt.Sprintf(fricasCmd, x))
+	if err != nil {
+		return 0, err
 	}
-	n, err := strconv.ParseFloat(string(num[:len(num)-1]), 64)
+	n, err := strconv.ParseFloat(raw, 64)
 	if err != nil && err.(*strconv.NumError).Err != strconv.ErrRange {
-		fmt.Fprintf(os.Stderr, "goFricas: strconv.ParseFloat(string(num[:len(num)-1]), 64): %v\n", err)
+		return 0, fmt.Errorf("goFricas: strconv.ParseFloat(%q, 64): %w", raw, err)
+	}
+	return n, nil
+}
+
+// FloatEval is the original, context-less API. It's equivalent to
+// EvalContext with context.Background, reporting errors to stderr and
+// returning NaN for backwards compatibility with existing callers.
+func FloatEval(f *FloatFricas, fricasCmd string, x float64) float64 {
+	n, err := f.EvalContext(context.Background(), fricasCmd, x)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goFricas: FloatEval: %v\n", err)
 		return math.NaN()
 	}
 	return n
 }
 
-func NewFloatFricas(f *FloatFricas) {
-	cmd := exec.Command("fricas", "-nosman", "-eval", ")set output algebra off", "-eval", ")lib )dir " + FricasLibDir,
-		"-eval", ")set history off", "-eval", ")set messages prompt none", "-eval", ")set messages type off",
-		"-eval", "bits(" + FloatFricasBits + ")$Float", "-eval", "outputGeneral(25)$Float", "-eval", "outputSpacing(0)$Float",
-		"-eval", ")set output algebra on")
-	var err error
-	f.in, err = cmd.StdinPipe()
+// BigFloatEval is like FloatEval, but both accepts and returns
+// *big.Float instead of float64, so that no precision is lost in
+// either direction. The result is rounded to f.FloatFricasBits bits
+// (or DefaultFloatFricasBits, if that field is empty).
+func BigFloatEval(f *FloatFricas, fricasCmd string, x *big.Float) (*big.Float, error) {
+	literal, err := bigFloatToFricasLiteral(x)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: cmd.StdinPipe: %v\n", err)
+		return nil, fmt.Errorf("goFricas: BigFloatEval: %w", err)
 	}
-	f.out, err = cmd.StdoutPipe()
+	raw, err := f.eval(context.Background(), fmt.Sprintf(fricasCmd, literal))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: cmd.StdoutPipe: %v\n", err)
+		return nil, fmt.Errorf("goFricas: BigFloatEval: %w", err)
 	}
-	if err = cmd.Start(); err != nil {
-		fmt.Fprintf(os.Stderr, "goFricas: cmd.Start: %v\n", err)
+
+	n, _, err := big.ParseFloat(raw, 10, f.bits(), big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("goFricas: big.ParseFloat(%q, ...): %w", raw, err)
+	}
+	return n, nil
+}
+
+// bits returns the configured precision, falling back to
+// DefaultFloatFricasBits when FloatFricasBits is unset.
+func (f *FloatFricas) bits() uint {
+	s := f.FloatFricasBits
+	if s == "" {
+		s = DefaultFloatFricasBits
 	}
-	f.inBuf, f.outBuf = bufio.NewWriter(f.in), bufio.NewReader(f.out)
-	for i := 0; i != FloatFricasLinesToSkipAtStartup; i++ {
-		if _, err = f.outBuf.ReadSlice('\n'); err != nil {
-			fmt.Fprintf(os.Stderr, "goFricas: f.outBuf.ReadSlice('\\n'): %v\n", err)
+	n, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goFricas: strconv.ParseUint(f.FloatFricasBits, 10, 0): %v\n", err)
+		return 0
+	}
+	return uint(n)
+}
+
+// bigFloatToFricasLiteral formats x as a FriCAS expression that
+// reconstructs it exactly, by coercing the exact numerator/denominator
+// fraction (from x.Rat) to Float rather than printing x's decimal
+// expansion, which would round. Fricas's Float has no representation
+// for infinity, and x.Rat reports that case by returning a nil *Rat,
+// so an infinite x is reported as an error rather than sent on as a
+// malformed expression.
+func bigFloatToFricasLiteral(x *big.Float) (string, error) {
+	r, _ := x.Rat(nil)
+	if r == nil {
+		return "", fmt.Errorf("goFricas: %v has no Fricas Float representation", x)
+	}
+	return fmt.Sprintf("((%s)/(%s))$Fraction(Integer)::Float", r.Num().String(), r.Denom().String()), nil
+}
+
+// Result is the reply to an Eval call: the raw text FriCAS printed for
+// the command, available as whichever typed representation fits the
+// command that produced it.
+type Result struct {
+	raw string
+}
+
+// Float64 parses the result as a FriCAS "0.xxxE±nn" style float,
+// rounding to float64.
+func (r Result) Float64() (float64, error) {
+	n, err := strconv.ParseFloat(r.raw, 64)
+	if err != nil && err.(*strconv.NumError).Err != strconv.ErrRange {
+		return 0, fmt.Errorf("goFricas: strconv.ParseFloat(%q, 64): %w", r.raw, err)
+	}
+	return n, nil
+}
+
+// BigFloat parses the result as a FriCAS "0.xxxE±nn" style float,
+// rounding to prec bits instead of to float64.
+func (r Result) BigFloat(prec uint) (*big.Float, error) {
+	n, _, err := big.ParseFloat(r.raw, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("goFricas: big.ParseFloat(%q, ...): %w", r.raw, err)
+	}
+	return n, nil
+}
+
+// Interval parses the result as a FriCAS "[a..b]" style interval.
+func (r Result) Interval() (lo, hi float64, err error) {
+	loStr, hiStr, err := parseIntervalText(r.raw)
+	if err != nil {
+		return 0, 0, err
+	}
+	if lo, err = strconv.ParseFloat(loStr, 64); err != nil {
+		return 0, 0, fmt.Errorf("goFricas: strconv.ParseFloat(loStr, 64): %w", err)
+	}
+	if hi, err = strconv.ParseFloat(hiStr, 64); err != nil {
+		return 0, 0, fmt.Errorf("goFricas: strconv.ParseFloat(hiStr, 64): %w", err)
+	}
+	return lo, hi, nil
+}
+
+// Eval formats cmd's arguments as FriCAS literals and evaluates it,
+// returning a Result the caller can read back in whichever
+// representation fits. Supported argument types are int64, *big.Int,
+// *big.Rat, *big.Float, float64, and [2]float64 (an interval).
+func (f *FloatFricas) Eval(fricasCmd string, args ...any) (Result, error) {
+	formatted := make([]any, len(args))
+	for i, a := range args {
+		s, err := formatFricasArg(a)
+		if err != nil {
+			return Result{}, fmt.Errorf("goFricas: formatting argument %d: %w", i, err)
 		}
+		formatted[i] = s
+	}
+	raw, err := f.eval(context.Background(), fmt.Sprintf(fricasCmd, formatted...))
+	if err != nil {
+		return Result{}, err
 	}
+	return Result{raw: raw}, nil
 }
 
-func (f *FloatFricas) Close() error {
-	if err := f.in.Close(); err != nil {
-		return err
+// formatFricasArg renders a Go value as the FriCAS literal syntax for
+// its type, losing no precision along the way.
+func formatFricasArg(a any) (string, error) {
+	switch v := a.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case *big.Int:
+		return v.String(), nil
+	case *big.Rat:
+		return fmt.Sprintf("((%s)/(%s))", v.Num().String(), v.Denom().String()), nil
+	case *big.Float:
+		return bigFloatToFricasLiteral(v)
+	case float64:
+		return bigFloatToFricasLiteral(new(big.Float).SetFloat64(v))
+	case [2]float64:
+		loLiteral, err := bigFloatToFricasLiteral(new(big.Float).SetFloat64(v[0]))
+		if err != nil {
+			return "", err
+		}
+		hiLiteral, err := bigFloatToFricasLiteral(new(big.Float).SetFloat64(v[1]))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("interval(%s,%s)$Interval(Float)", loLiteral, hiLiteral), nil
+	default:
+		return "", fmt.Errorf("unsupported argument type %T", a)
+	}
+}
+
+func NewFloatFricas(f *FloatFricas) error {
+	evalArgs := []string{
+		")set output algebra off",
+		")lib )dir " + FricasLibDir,
+		")set history off",
+		")set messages prompt string \"" + promptSentinel + "\"",
+		")set messages type off",
+		"bits(" + strconv.FormatUint(uint64(f.bits()), 10) + ")$Float",
+		"outputGeneral(25)$Float",
+		"outputSpacing(0)$Float",
+		")set output algebra on",
+	}
+	s, err := startSession(evalArgs)
+	if err != nil {
+		return fmt.Errorf("goFricas: NewFloatFricas: %w", err)
+	}
+	f.session = s
+	return nil
+}
+
+// IntervalFricas wraps a Fricas session with the Interval package
+// loaded, for rigorous enclosure arithmetic over Float.
+type IntervalFricas struct {
+	*session
+
+	// FloatFricasBits is the bits of precision for Fricas's Float
+	// domain, which bounds how tight the Interval(Float) endpoints
+	// Fricas itself can compute are. Must be set before calling
+	// NewIntervalFricas; leave it empty to fall back to
+	// DefaultFloatFricasBits.
+	FloatFricasBits string
+}
+
+// bits returns the configured precision, falling back to
+// DefaultFloatFricasBits when FloatFricasBits is unset.
+func (f *IntervalFricas) bits() uint {
+	s := f.FloatFricasBits
+	if s == "" {
+		s = DefaultFloatFricasBits
+	}
+	n, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "goFricas: strconv.ParseUint(f.FloatFricasBits, 10, 0): %v\n", err)
+		return 0
+	}
+	return uint(n)
+}
+
+// NewIntervalFricas starts a Fricas subprocess with Interval(Float)
+// ready to use, with Fricas's own Float precision set to f.bits() so
+// the computed enclosures are no looser than the precision callers
+// ask for.
+func NewIntervalFricas(f *IntervalFricas) error {
+	evalArgs := []string{
+		")set output algebra off",
+		")lib )dir " + FricasLibDir,
+		")set history off",
+		")set messages prompt string \"" + promptSentinel + "\"",
+		")set messages type off",
+		"bits(" + strconv.FormatUint(uint64(f.bits()), 10) + ")$Float",
+		"outputGeneral(25)$Float",
+		"outputSpacing(0)$Float",
+		")set output algebra on",
+	}
+	s, err := startSession(evalArgs)
+	if err != nil {
+		return fmt.Errorf("goFricas: NewIntervalFricas: %w", err)
+	}
+	f.session = s
+	return nil
+}
+
+// EvalInterval substitutes the interval [lo, hi] into fricasCmd as an
+// Interval(Float) literal and parses Fricas's "[a..b]" style reply
+// back into two float64 endpoints.
+func EvalInterval(f *IntervalFricas, fricasCmd string, lo, hi float64) (lo2, hi2 float64, err error) {
+	arg := fmt.Sprintf("interval(%v,%v)$Interval(Float)", lo, hi)
+	raw, err := f.eval(context.Background(), fmt.Sprintf(fricasCmd, arg))
+	if err != nil {
+		return 0, 0, fmt.Errorf("goFricas: EvalInterval: %w", err)
+	}
+	loStr, hiStr, err := parseIntervalText(raw)
+	if err != nil {
+		return 0, 0, fmt.Errorf("goFricas: parseIntervalText: %w", err)
+	}
+	lo2, err = strconv.ParseFloat(loStr, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("goFricas: strconv.ParseFloat(loStr, 64): %w", err)
+	}
+	hi2, err = strconv.ParseFloat(hiStr, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("goFricas: strconv.ParseFloat(hiStr, 64): %w", err)
+	}
+	return lo2, hi2, nil
+}
+
+// EvalIntervalBig is like EvalInterval, but both accepts and returns
+// *big.Float endpoints so no precision is lost at the Go/Fricas
+// boundary. The endpoints are rounded to prec bits.
+func EvalIntervalBig(f *IntervalFricas, fricasCmd string, lo, hi *big.Float, prec uint) (lo2, hi2 *big.Float, err error) {
+	loLiteral, err := bigFloatToFricasLiteral(lo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("goFricas: EvalIntervalBig: %w", err)
+	}
+	hiLiteral, err := bigFloatToFricasLiteral(hi)
+	if err != nil {
+		return nil, nil, fmt.Errorf("goFricas: EvalIntervalBig: %w", err)
+	}
+	arg := fmt.Sprintf("interval(%s,%s)$Interval(Float)", loLiteral, hiLiteral)
+	raw, err := f.eval(context.Background(), fmt.Sprintf(fricasCmd, arg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("goFricas: EvalIntervalBig: %w", err)
+	}
+	loStr, hiStr, err := parseIntervalText(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("goFricas: parseIntervalText: %w", err)
+	}
+	lo2, _, err = big.ParseFloat(loStr, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, nil, fmt.Errorf("goFricas: big.ParseFloat(%q, ...): %w", loStr, err)
+	}
+	hi2, _, err = big.ParseFloat(hiStr, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, nil, fmt.Errorf("goFricas: big.ParseFloat(%q, ...): %w", hiStr, err)
+	}
+	return lo2, hi2, nil
+}
+
+// parseIntervalText splits a FriCAS "[a..b]" style interval into its
+// two endpoints, normalized so strconv/big.ParseFloat can read them.
+func parseIntervalText(s string) (loStr, hiStr string, err error) {
+	s = strings.TrimPrefix(s, "[")
+	s = strings.TrimSuffix(s, "]")
+	parts := strings.SplitN(s, "..", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("unexpected interval output: %q", s)
+	}
+	normalize := func(x string) string {
+		x = strings.TrimSpace(x)
+		if strings.HasPrefix(x, "- ") {
+			x = "-" + x[len("- "):]
+		}
+		return x
 	}
-	return f.out.Close()
+	return normalize(parts[0]), normalize(parts[1]), nil
 }
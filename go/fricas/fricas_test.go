@@ -0,0 +1,297 @@
+package fricas
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseIntervalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantLo  string
+		wantHi  string
+		wantErr bool
+	}{
+		{name: "simple", s: "[0.1..0.2]", wantLo: "0.1", wantHi: "0.2"},
+		{name: "negative endpoints", s: "[- 0.33..- 0.44]", wantLo: "-0.33", wantHi: "-0.44"},
+		{name: "no brackets", s: "0.1..0.2", wantLo: "0.1", wantHi: "0.2"},
+		{name: "no separator", s: "[0.1]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi, err := parseIntervalText(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseIntervalText(%q) = %q, %q, nil; want error", tt.s, lo, hi)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseIntervalText(%q): %v", tt.s, err)
+			}
+			if lo != tt.wantLo || hi != tt.wantHi {
+				t.Errorf("parseIntervalText(%q) = %q, %q; want %q, %q", tt.s, lo, hi, tt.wantLo, tt.wantHi)
+			}
+		})
+	}
+}
+
+func TestSplitOnPrompt(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		atEOF       bool
+		wantAdvance int
+		wantToken   string
+	}{
+		{
+			name:        "sentinel present",
+			data:        "(1)  0.33" + promptSentinel,
+			atEOF:       false,
+			wantAdvance: len("(1)  0.33" + promptSentinel),
+			wantToken:   "(1)  0.33",
+		},
+		{
+			name:        "sentinel absent, more data expected",
+			data:        "(1)  0.3",
+			atEOF:       false,
+			wantAdvance: 0,
+			wantToken:   "",
+		},
+		{
+			name:        "sentinel absent at EOF",
+			data:        "(1)  0.3",
+			atEOF:       true,
+			wantAdvance: len("(1)  0.3"),
+			wantToken:   "(1)  0.3",
+		},
+		{
+			name:        "trailing data kept for next token",
+			data:        "(1)  0.33" + promptSentinel + "(2)",
+			atEOF:       false,
+			wantAdvance: len("(1)  0.33" + promptSentinel),
+			wantToken:   "(1)  0.33",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			advance, token, err := splitOnPrompt([]byte(tt.data), tt.atEOF)
+			if err != nil {
+				t.Fatalf("splitOnPrompt(%q, %v): %v", tt.data, tt.atEOF, err)
+			}
+			if advance != tt.wantAdvance || string(token) != tt.wantToken {
+				t.Errorf("splitOnPrompt(%q, %v) = %d, %q; want %d, %q",
+					tt.data, tt.atEOF, advance, token, tt.wantAdvance, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestStripResultMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		block  string
+		want   string
+		wantOk bool
+	}{
+		{name: "simple", block: "(1)  0.33E1", want: "0.33E1", wantOk: true},
+		{name: "multi-digit counter", block: "(42)  [0.1..0.2]", want: "[0.1..0.2]", wantOk: true},
+		{name: "no parens", block: "0.33E1", wantOk: false},
+		{name: "non-numeric inside parens", block: "(abc)  0.33E1", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := stripResultMarker(tt.block)
+			if ok != tt.wantOk {
+				t.Fatalf("stripResultMarker(%q) ok = %v, want %v", tt.block, ok, tt.wantOk)
+			}
+			if ok && got != tt.want {
+				t.Errorf("stripResultMarker(%q) = %q, want %q", tt.block, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFricasArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     any
+		want    string
+		wantErr bool
+	}{
+		{name: "int64", arg: int64(-7), want: "-7"},
+		{name: "big.Int", arg: big.NewInt(42), want: "42"},
+		{name: "big.Rat", arg: big.NewRat(3, 4), want: "((3)/(4))"},
+		{name: "big.Float", arg: big.NewFloat(1.5), want: "((3)/(2))$Fraction(Integer)::Float"},
+		{name: "float64", arg: 1.5, want: "((3)/(2))$Fraction(Integer)::Float"},
+		{name: "float64 whole number", arg: 100.0, want: "((100)/(1))$Fraction(Integer)::Float"},
+		{name: "float64 large magnitude", arg: 1e20, want: "((100000000000000000000)/(1))$Fraction(Integer)::Float"},
+		{name: "float64 small magnitude", arg: 1e-10, want: "((7737125245533627)/(77371252455336267181195264))$Fraction(Integer)::Float"},
+		{
+			name: "interval",
+			arg:  [2]float64{1, 2},
+			want: "interval(((1)/(1))$Fraction(Integer)::Float,((2)/(1))$Fraction(Integer)::Float)$Interval(Float)",
+		},
+		{name: "unsupported", arg: "nope", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatFricasArg(tt.arg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("formatFricasArg(%v) = %q, nil; want error", tt.arg, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("formatFricasArg(%v): %v", tt.arg, err)
+			}
+			if got != tt.want {
+				t.Errorf("formatFricasArg(%v) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBigFloatToFricasLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		x       *big.Float
+		want    string
+		wantErr bool
+	}{
+		{name: "zero", x: big.NewFloat(0), want: "((0)/(1))$Fraction(Integer)::Float"},
+		{name: "one", x: big.NewFloat(1), want: "((1)/(1))$Fraction(Integer)::Float"},
+		{name: "negative fraction", x: big.NewFloat(-1.5), want: "((-3)/(2))$Fraction(Integer)::Float"},
+		{name: "+Inf", x: new(big.Float).SetInf(false), wantErr: true},
+		{name: "-Inf", x: new(big.Float).SetInf(true), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bigFloatToFricasLiteral(tt.x)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("bigFloatToFricasLiteral(%v) = %q, nil; want error", tt.x, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bigFloatToFricasLiteral(%v): %v", tt.x, err)
+			}
+			if !strings.HasSuffix(got, "$Fraction(Integer)::Float") {
+				t.Fatalf("bigFloatToFricasLiteral(%v) = %q, want a \"((num)/(denom))$Fraction(Integer)::Float\" literal", tt.x, got)
+			}
+			if got != tt.want {
+				t.Errorf("bigFloatToFricasLiteral(%v) = %q, want %q", tt.x, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeSession wires a session's in/out up to an in-memory pipe pair, with
+// a scripted "fricas" on the other end: it reads newline-terminated
+// requests and replies with whatever replies maps them to, each wrapped
+// in a result-counter marker and promptSentinel the same way a real
+// Fricas session's output is framed. Requests not found in replies make
+// the fake server reply with an unrecognized-output error block, so a
+// test that sees requests arrive garbled (e.g. from a dispatch bug that
+// interleaves concurrent writers) fails instead of hanging.
+func fakeSession(t *testing.T, replies map[string]string, delay map[string]time.Duration) *session {
+	t.Helper()
+	fromClient, toServer := io.Pipe()
+	fromServer, toClient := io.Pipe()
+
+	s := &session{
+		in:  toServer,
+		out: fromServer,
+	}
+	s.inBuf = bufio.NewWriter(s.in)
+	s.scan = bufio.NewScanner(s.out)
+	s.scan.Split(splitOnPrompt)
+
+	go func() {
+		lines := bufio.NewScanner(fromClient)
+		for lines.Scan() {
+			line := lines.Text()
+			if d, ok := delay[line]; ok {
+				time.Sleep(d)
+			}
+			reply, ok := replies[line]
+			if !ok {
+				reply = "$$$ unrecognized request: " + line
+			}
+			io.WriteString(toClient, "(1)  "+reply+promptSentinel)
+		}
+	}()
+
+	t.Cleanup(func() {
+		s.in.Close()
+		s.out.Close()
+	})
+	return s
+}
+
+func TestSessionEvalConcurrentCallsDoNotInterleave(t *testing.T) {
+	s := fakeSession(t, map[string]string{
+		"cmd-A": "11",
+		"cmd-B": "22",
+	}, nil)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	var gotA, gotB string
+	var errA, errB error
+	go func() {
+		defer wg.Done()
+		gotA, errA = s.eval(context.Background(), "cmd-A\n")
+	}()
+	go func() {
+		defer wg.Done()
+		gotB, errB = s.eval(context.Background(), "cmd-B\n")
+	}()
+	wg.Wait()
+
+	if errA != nil {
+		t.Fatalf("eval(cmd-A): %v", errA)
+	}
+	if errB != nil {
+		t.Fatalf("eval(cmd-B): %v", errB)
+	}
+	if gotA != "11" {
+		t.Errorf("eval(cmd-A) = %q, want %q", gotA, "11")
+	}
+	if gotB != "22" {
+		t.Errorf("eval(cmd-B) = %q, want %q", gotB, "22")
+	}
+}
+
+func TestSessionEvalCancelDoesNotWedgeNextCall(t *testing.T) {
+	s := fakeSession(t, map[string]string{
+		"slow-cmd": "99",
+		"cmd-C":    "33",
+	}, map[string]time.Duration{
+		"slow-cmd": 80 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.eval(ctx, "slow-cmd\n"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("eval(slow-cmd) error = %v, want context.DeadlineExceeded", err)
+	}
+
+	got, err := s.eval(context.Background(), "cmd-C\n")
+	if err != nil {
+		t.Fatalf("eval(cmd-C): %v", err)
+	}
+	if got != "33" {
+		t.Errorf("eval(cmd-C) = %q, want %q", got, "33")
+	}
+}